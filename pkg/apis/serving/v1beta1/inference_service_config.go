@@ -0,0 +1,48 @@
+/*
+Copyright 2020 kubeflow.org.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// InferenceServicesConfig is the cluster-wide configuration for the InferenceService
+// controller, loaded from the inferenceservice-config ConfigMap.
+type InferenceServicesConfig struct {
+	// EnableSidecarContainers switches logger/batcher/model-puller injection from
+	// annotation-driven webhook mutation to appending sidecar containers directly onto
+	// the predictor's PodSpec.
+	EnableSidecarContainers bool `json:"enableSidecarContainers,omitempty"`
+	// Logger is the image used for the logger sidecar when EnableSidecarContainers is set.
+	Logger LoggerConfig `json:"logger,omitempty"`
+	// Batcher is the image used for the batcher sidecar when EnableSidecarContainers is set.
+	Batcher BatcherConfig `json:"batcher,omitempty"`
+	// ModelPuller is the image used for the multi-model puller sidecar when
+	// EnableSidecarContainers is set.
+	ModelPuller ModelPullerConfig `json:"modelPuller,omitempty"`
+}
+
+// LoggerConfig configures the inference logger sidecar/container image.
+type LoggerConfig struct {
+	Image string `json:"image,omitempty"`
+}
+
+// BatcherConfig configures the request batcher sidecar/container image.
+type BatcherConfig struct {
+	Image string `json:"image,omitempty"`
+}
+
+// ModelPullerConfig configures the multi-model puller sidecar/container image.
+type ModelPullerConfig struct {
+	Image string `json:"image,omitempty"`
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2020 kubeflow.org.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package constants
+
+// DeploymentModeType is the value of the DeploymentMode annotation.
+type DeploymentModeType string
+
+const (
+	// DeploymentMode is set on an InferenceService to choose how its components are
+	// reconciled: as Knative Services (Serverless, the default) or as native Kubernetes
+	// Deployments (RawDeployment), for clusters without Knative Serving installed.
+	DeploymentMode = "serving.kubeflow.org/deploymentMode"
+
+	RawDeployment DeploymentModeType = "RawDeployment"
+	Serverless    DeploymentModeType = "Serverless"
+)
+
+// DefaultCPUUtilization is the target CPU utilization percentage every RawDeployment
+// component's HorizontalPodAutoscaler scales on.
+const DefaultCPUUtilization = int32(80)
+
+// InferenceServiceDefaultHttpPort is the container port a RawDeployment component's
+// Service routes HTTP traffic to.
+const InferenceServiceDefaultHttpPort = 8080
@@ -0,0 +1,45 @@
+/*
+Copyright 2020 kubeflow.org.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package constants
+
+import "fmt"
+
+// Sidecar container names used when InferenceServicesConfig.EnableSidecarContainers is
+// set, in place of the annotation-driven webhook injection.
+const (
+	LoggerContainerName      = "kfserving-logger-container"
+	BatcherContainerName     = "kfserving-batcher-container"
+	ModelPullerContainerName = "kfserving-model-puller-container"
+)
+
+// ModelDirVolumeName/ModelDirMountPath name the emptyDir volume the model-puller sidecar
+// syncs multi-model server artifacts into, shared with the predictor container.
+const (
+	ModelDirVolumeName = "kfserving-model-dir"
+	ModelDirMountPath  = "/mnt/models"
+)
+
+// MultiModelInternalAnnotationKey marks an InferenceService whose predictor is running in
+// multi-model server mode, set by the TrainedModel controller once it has attached
+// TrainedModels to the InferenceService.
+const MultiModelInternalAnnotationKey = "internal.serving.kubeflow.org/multi-model"
+
+// DefaultMultiModelConfigMapName returns the name of the multi-model ConfigMap shard for
+// the InferenceService named isvcName.
+func DefaultMultiModelConfigMapName(isvcName string) string {
+	return fmt.Sprintf("modelconfig-%s", isvcName)
+}
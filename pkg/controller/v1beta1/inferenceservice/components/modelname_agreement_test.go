@@ -0,0 +1,155 @@
+/*
+Copyright 2020 kubeflow.org.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"testing"
+
+	"github.com/kubeflow/kfserving/pkg/constants"
+	"github.com/kubeflow/kfserving/pkg/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeflow/kfserving/pkg/apis/serving/v1beta1"
+)
+
+func newTestReconcilerScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add appsv1 to scheme: %v", err)
+	}
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add corev1 to scheme: %v", err)
+	}
+	if err := autoscalingv2beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add autoscalingv2beta1 to scheme: %v", err)
+	}
+	if err := v1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add v1beta1 to scheme: %v", err)
+	}
+	return scheme
+}
+
+// TestModelNameAgreement_TransformerAdoptsPredictorsModelName verifies that once the
+// predictor container's MODEL_NAME has been resolved and set, propagating that name to the
+// transformer container via setModelNameEnvVar leaves both containers agreeing with
+// utils.GetModelName.
+func TestModelNameAgreement_TransformerAdoptsPredictorsModelName(t *testing.T) {
+	isvc := newTestPredictorIsvc()
+	isvc.Spec.Predictor.Logger = nil
+	isvc.Spec.Predictor.Custom.Spec.Containers[0].Env = []v1.EnvVar{
+		{Name: "MODEL_NAME", Value: "custom-name"},
+	}
+	isvc.Spec.Transformer = &v1beta1.TransformerSpec{
+		Custom: &v1beta1.CustomTransformer{
+			PodTemplateSpec: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{Name: "transformer-container"}},
+				},
+			},
+		},
+	}
+
+	modelName := utils.GetModelName(isvc)
+	if modelName != "custom-name" {
+		t.Fatalf("expected predictor's MODEL_NAME to resolve, got %q", modelName)
+	}
+	setModelNameEnvVar(&isvc.Spec.Transformer.Custom.Spec.Containers[0], modelName)
+
+	transformerName, ok := modelNameEnvVarValue(isvc.Spec.Transformer.Custom.Spec.Containers[0])
+	if !ok || transformerName != modelName {
+		t.Errorf("expected transformer MODEL_NAME %q to agree with predictor, got %q (set=%v)", modelName, transformerName, ok)
+	}
+}
+
+// TestPredictorReconcile_PreservesUserSetModelName calls Predictor.Reconcile end-to-end
+// (RawDeployment mode, so it never depends on the unimplemented knative reconciler) to
+// verify that predictor.GetContainer re-deriving Containers[0] does not clobber a
+// user-set MODEL_NAME before utils.GetModelName reads it back.
+func TestPredictorReconcile_PreservesUserSetModelName(t *testing.T) {
+	scheme := newTestReconcilerScheme(t)
+	isvc := newTestPredictorIsvc()
+	isvc.Spec.Predictor.Logger = nil
+	isvc.Spec.Predictor.Custom.Spec.Containers[0].Env = []v1.EnvVar{
+		{Name: "MODEL_NAME", Value: "custom-name"},
+	}
+	minReplicas := 1
+	isvc.Spec.Predictor.ComponentExtensionSpec = v1beta1.ComponentExtensionSpec{MinReplicas: &minReplicas, MaxReplicas: 1}
+	isvc.Annotations = map[string]string{constants.DeploymentMode: string(constants.RawDeployment)}
+
+	p := NewPredictor(fake.NewClientBuilder().WithScheme(scheme).Build(), scheme, &v1beta1.InferenceServicesConfig{})
+	if err := p.Reconcile(isvc); err != nil {
+		t.Fatalf("Predictor.Reconcile returned error: %v", err)
+	}
+
+	name, ok := modelNameEnvVarValue(isvc.Spec.Predictor.Custom.Spec.Containers[0])
+	if !ok || name != "custom-name" {
+		t.Errorf("expected predictor container to keep the user-set MODEL_NAME after Reconcile, got %q (set=%v)", name, ok)
+	}
+}
+
+// TestTransformerReconcile_AdoptsPredictorsModelName calls both Predictor.Reconcile and
+// Transformer.Reconcile end-to-end and asserts the transformer container ends up agreeing
+// with the predictor's resolved model name, not whatever its own framework/container
+// construction would have picked.
+func TestTransformerReconcile_AdoptsPredictorsModelName(t *testing.T) {
+	scheme := newTestReconcilerScheme(t)
+	isvc := newTestPredictorIsvc()
+	isvc.Spec.Predictor.Logger = nil
+	isvc.Spec.Predictor.Custom.Spec.Containers[0].Env = []v1.EnvVar{
+		{Name: "MODEL_NAME", Value: "custom-name"},
+	}
+	minReplicas := 1
+	isvc.Spec.Predictor.ComponentExtensionSpec = v1beta1.ComponentExtensionSpec{MinReplicas: &minReplicas, MaxReplicas: 1}
+	isvc.Spec.Transformer = &v1beta1.TransformerSpec{
+		ComponentExtensionSpec: v1beta1.ComponentExtensionSpec{MinReplicas: &minReplicas, MaxReplicas: 1},
+		Custom: &v1beta1.CustomTransformer{
+			PodTemplateSpec: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{Name: "transformer-container"}},
+				},
+			},
+		},
+	}
+	isvc.Annotations = map[string]string{constants.DeploymentMode: string(constants.RawDeployment)}
+
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+	predictor := NewPredictor(client, scheme, &v1beta1.InferenceServicesConfig{})
+	if err := predictor.Reconcile(isvc); err != nil {
+		t.Fatalf("Predictor.Reconcile returned error: %v", err)
+	}
+	transformer := NewTransformer(client, scheme, &v1beta1.InferenceServicesConfig{})
+	if err := transformer.Reconcile(isvc); err != nil {
+		t.Fatalf("Transformer.Reconcile returned error: %v", err)
+	}
+
+	predictorName, _ := modelNameEnvVarValue(isvc.Spec.Predictor.Custom.Spec.Containers[0])
+	transformerName, ok := modelNameEnvVarValue(isvc.Spec.Transformer.Custom.Spec.Containers[0])
+	if !ok || transformerName != predictorName {
+		t.Errorf("expected transformer MODEL_NAME %q to agree with predictor MODEL_NAME %q", transformerName, predictorName)
+	}
+}
+
+func modelNameEnvVarValue(container v1.Container) (string, bool) {
+	for _, env := range container.Env {
+		if env.Name == "MODEL_NAME" {
+			return env.Value, true
+		}
+	}
+	return "", false
+}
@@ -16,6 +16,7 @@ package components
 import (
 	"github.com/go-logr/logr"
 	"github.com/kubeflow/kfserving/pkg/constants"
+	"github.com/kubeflow/kfserving/pkg/controller/v1beta1/inferenceservice/reconcilers/deployment"
 	"github.com/kubeflow/kfserving/pkg/controller/v1beta1/inferenceservice/reconcilers/knative"
 	"github.com/kubeflow/kfserving/pkg/credentials"
 	"github.com/kubeflow/kfserving/pkg/utils"
@@ -57,13 +58,20 @@ func (p *Predictor) Reconcile(isvc *v1beta1.InferenceService) error {
 	annotations := utils.Filter(isvc.Annotations, func(key string) bool {
 		return !utils.Includes(constants.ServiceAnnotationDisallowedList, key)
 	})
+	isMMSPredictor := utils.IsMMSPredictor(isvc)
 	// KNative does not support INIT containers or mounting, so we add annotations that trigger the
-	// StorageInitializer injector to mutate the underlying deployment to provision model data
-	if sourceURI := predictor.GetStorageUri(); sourceURI != nil {
+	// StorageInitializer injector to mutate the underlying deployment to provision model data.
+	// In multi-model server mode there is no single model to provision; the model-puller
+	// sidecar syncs models from the multi-model ConfigMap instead.
+	if sourceURI := predictor.GetStorageUri(); sourceURI != nil && !isMMSPredictor {
 		annotations[constants.StorageInitializerSourceUriInternalAnnotationKey] = *sourceURI
 	}
-	hasInferenceLogging := addLoggerAnnotations(isvc.Spec.Predictor.Logger, annotations)
-	hasInferenceBatcher := addBatcherAnnotations(isvc.Spec.Predictor.Batcher, annotations)
+	useSidecarContainers := p.inferenceServiceConfig.EnableSidecarContainers
+	var hasInferenceLogging, hasInferenceBatcher bool
+	if !useSidecarContainers {
+		hasInferenceLogging = addLoggerAnnotations(isvc.Spec.Predictor.Logger, annotations)
+		hasInferenceBatcher = addBatcherAnnotations(isvc.Spec.Predictor.Batcher, annotations)
+	}
 
 	objectMeta := metav1.ObjectMeta{
 		Name:      isvc.Name + "-" + string(v1beta1.PredictorComponent),
@@ -89,17 +97,43 @@ func (p *Predictor) Reconcile(isvc *v1beta1.InferenceService) error {
 		container := predictor.GetContainer(isvc.ObjectMeta, isvc.Spec.Predictor.GetExtensions(), p.inferenceServiceConfig)
 		isvc.Spec.Predictor.Custom.Spec.Containers[0] = *container
 	}
-	//TODO now knative supports multi containers, consolidate logger/batcher/puller to the sidecar container
-	//https://github.com/kubeflow/kfserving/issues/973
-	if hasInferenceLogging {
-		addLoggerContainerPort(&isvc.Spec.Predictor.Custom.Spec.Containers[0])
+	if isMMSPredictor {
+		// The model-puller sidecar owns model loading in MMS mode, so strip any
+		// STORAGE_URI/MODEL_NAME env vars the framework implementation injected.
+		stripSingleModelEnvVars(&isvc.Spec.Predictor.Custom.Spec.Containers[0])
+	} else {
+		setModelNameEnvVar(&isvc.Spec.Predictor.Custom.Spec.Containers[0], utils.GetModelName(isvc))
 	}
+	if useSidecarContainers {
+		// Knative now supports multi-container Pods, so rather than annotating the
+		// predictor and relying on a webhook to inject the sidecars, add them directly.
+		// https://github.com/kubeflow/kfserving/issues/973
+		addSidecarContainers(isvc, p.inferenceServiceConfig)
+	} else {
+		if hasInferenceLogging {
+			addLoggerContainerPort(&isvc.Spec.Predictor.Custom.Spec.Containers[0])
+		}
 
-	if hasInferenceBatcher {
-		addBatcherContainerPort(&isvc.Spec.Predictor.Custom.Spec.Containers[0])
+		if hasInferenceBatcher {
+			addBatcherContainerPort(&isvc.Spec.Predictor.Custom.Spec.Containers[0])
+		}
 	}
 
 	// Here we allow switch between knative and vanilla deployment
+	if getDeploymentMode(isvc.Annotations) == constants.RawDeployment {
+		r, err := deployment.NewDeploymentReconciler(p.client, p.scheme, objectMeta, &isvc.Spec.Predictor.ComponentExtensionSpec,
+			&isvc.Spec.Predictor.Custom.Spec, isvc.Status.Components[v1beta1.PredictorComponent], isvc)
+		if err != nil {
+			return err
+		}
+		status, err := r.Reconcile()
+		if err != nil {
+			return err
+		}
+		isvc.Status.PropagateStatus(v1beta1.PredictorComponent, status)
+		return nil
+	}
+
 	r := knative.NewKsvcReconciler(p.client, p.scheme, objectMeta, &isvc.Spec.Predictor.ComponentExtensionSpec,
 		&isvc.Spec.Predictor.Custom.Spec, isvc.Status.Components[v1beta1.PredictorComponent])
 
@@ -114,6 +148,16 @@ func (p *Predictor) Reconcile(isvc *v1beta1.InferenceService) error {
 	}
 }
 
+// getDeploymentMode returns the deployment mode requested via the
+// constants.DeploymentMode annotation, defaulting to Serverless (Knative) when unset or
+// unrecognized.
+func getDeploymentMode(annotations map[string]string) constants.DeploymentModeType {
+	if mode, ok := annotations[constants.DeploymentMode]; ok && constants.DeploymentModeType(mode) == constants.RawDeployment {
+		return constants.RawDeployment
+	}
+	return constants.Serverless
+}
+
 func addLoggerAnnotations(logger *v1beta1.LoggerSpec, annotations map[string]string) bool {
 	if logger != nil {
 		annotations[constants.LoggerInternalAnnotationKey] = "true"
@@ -172,3 +216,124 @@ func addBatcherContainerPort(container *v1.Container) {
 		}
 	}
 }
+
+// addSidecarContainers appends a logger, batcher and (for multi-model predictors) a
+// model-puller container to isvc.Spec.Predictor.Custom.Spec.Containers instead of relying
+// on annotation-driven webhook injection. The outermost sidecar, if any, takes over the
+// KService's traffic port so requests are routed through it to the predictor.
+func addSidecarContainers(isvc *v1beta1.InferenceService, config *v1beta1.InferenceServicesConfig) {
+	spec := &isvc.Spec.Predictor.Custom.Spec
+	trafficContainerIndex := -1
+
+	if logger := isvc.Spec.Predictor.Logger; logger != nil {
+		spec.Containers = append(spec.Containers, buildLoggerContainer(logger, config))
+		trafficContainerIndex = len(spec.Containers) - 1
+	}
+
+	if batcher := isvc.Spec.Predictor.Batcher; batcher != nil {
+		spec.Containers = append(spec.Containers, buildBatcherContainer(batcher, config))
+		trafficContainerIndex = len(spec.Containers) - 1
+	}
+
+	if utils.IsMMSPredictor(isvc) {
+		spec.Containers = append(spec.Containers, buildModelPullerContainer(isvc, config))
+		spec.Volumes = append(spec.Volumes, v1.Volume{
+			Name:         constants.ModelDirVolumeName,
+			VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+		})
+		spec.Containers[0].VolumeMounts = append(spec.Containers[0].VolumeMounts, v1.VolumeMount{
+			Name:      constants.ModelDirVolumeName,
+			MountPath: constants.ModelDirMountPath,
+		})
+	}
+
+	// Do the ports swap by index, after every append that could grow (and reallocate)
+	// spec.Containers, so we never hold a pointer into a backing array that's since been
+	// replaced.
+	if trafficContainerIndex >= 0 {
+		spec.Containers[trafficContainerIndex].Ports = spec.Containers[0].Ports
+		spec.Containers[0].Ports = nil
+	}
+}
+
+func buildLoggerContainer(logger *v1beta1.LoggerSpec, config *v1beta1.InferenceServicesConfig) v1.Container {
+	sinkUrl := ""
+	if logger.URL != nil {
+		sinkUrl = *logger.URL
+	}
+	port, _ := strconv.Atoi(constants.InferenceServiceDefaultLoggerPort)
+	return v1.Container{
+		Name:  constants.LoggerContainerName,
+		Image: config.Logger.Image,
+		Args: []string{
+			"--sink-url", sinkUrl,
+			"--mode", string(logger.Mode),
+			"--port", constants.InferenceServiceDefaultLoggerPort,
+		},
+		Ports: []v1.ContainerPort{{ContainerPort: int32(port)}},
+	}
+}
+
+func buildBatcherContainer(batcher *v1beta1.Batcher, config *v1beta1.InferenceServicesConfig) v1.Container {
+	args := []string{"--port", constants.InferenceServiceDefaultBatcherPort}
+	if batcher.MaxBatchSize != nil {
+		args = append(args, "--max-batchsize", strconv.Itoa(*batcher.MaxBatchSize))
+	}
+	if batcher.MaxLatency != nil {
+		args = append(args, "--max-latency", strconv.Itoa(*batcher.MaxLatency))
+	}
+	if batcher.Timeout != nil {
+		args = append(args, "--timeout", strconv.Itoa(*batcher.Timeout))
+	}
+	port, _ := strconv.Atoi(constants.InferenceServiceDefaultBatcherPort)
+	return v1.Container{
+		Name:  constants.BatcherContainerName,
+		Image: config.Batcher.Image,
+		Args:  args,
+		Ports: []v1.ContainerPort{{ContainerPort: int32(port)}},
+	}
+}
+
+func buildModelPullerContainer(isvc *v1beta1.InferenceService, config *v1beta1.InferenceServicesConfig) v1.Container {
+	return v1.Container{
+		Name:  constants.ModelPullerContainerName,
+		Image: config.ModelPuller.Image,
+		Args: []string{
+			"--configmap-name", constants.DefaultMultiModelConfigMapName(isvc.Name),
+			"--model-dir", constants.ModelDirMountPath,
+		},
+		VolumeMounts: []v1.VolumeMount{
+			{
+				Name:      constants.ModelDirVolumeName,
+				MountPath: constants.ModelDirMountPath,
+			},
+		},
+	}
+}
+
+// stripSingleModelEnvVars removes the STORAGE_URI/MODEL_NAME env vars a framework
+// implementation's GetContainer set, since MMS predictors get their models from the
+// model-puller sidecar instead.
+func stripSingleModelEnvVars(container *v1.Container) {
+	filtered := container.Env[:0]
+	for _, env := range container.Env {
+		if env.Name == constants.StorageUriEnvVarKey || env.Name == constants.ModelNameEnvVarKey {
+			continue
+		}
+		filtered = append(filtered, env)
+	}
+	container.Env = filtered
+}
+
+// setModelNameEnvVar ensures container's MODEL_NAME env var agrees with modelName,
+// overwriting it if already set so the predictor and any downstream transformer always
+// register the same model name.
+func setModelNameEnvVar(container *v1.Container, modelName string) {
+	for i, env := range container.Env {
+		if env.Name == constants.ModelNameEnvVarKey {
+			container.Env[i].Value = modelName
+			return
+		}
+	}
+	container.Env = append(container.Env, v1.EnvVar{Name: constants.ModelNameEnvVarKey, Value: modelName})
+}
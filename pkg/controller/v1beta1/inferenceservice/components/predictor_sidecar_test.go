@@ -0,0 +1,118 @@
+/*
+Copyright 2020 kubeflow.org.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"testing"
+
+	"github.com/kubeflow/kfserving/pkg/constants"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/kfserving/pkg/apis/serving/v1beta1"
+)
+
+func newTestPredictorIsvc() *v1beta1.InferenceService {
+	return &v1beta1.InferenceService{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-model", Namespace: "default"},
+		Spec: v1beta1.InferenceServiceSpec{
+			Predictor: v1beta1.PredictorSpec{
+				Custom: &v1beta1.CustomPredictor{
+					PodTemplateSpec: v1.PodTemplateSpec{
+						Spec: v1.PodSpec{
+							Containers: []v1.Container{
+								{
+									Name:  "kfserving-container",
+									Image: "sklearnserver:latest",
+									Ports: []v1.ContainerPort{{ContainerPort: 8080}},
+								},
+							},
+						},
+					},
+				},
+				Logger: &v1beta1.LoggerSpec{},
+			},
+		},
+	}
+}
+
+func testSidecarConfig() *v1beta1.InferenceServicesConfig {
+	return &v1beta1.InferenceServicesConfig{
+		Logger:      v1beta1.LoggerConfig{Image: "logger:latest"},
+		Batcher:     v1beta1.BatcherConfig{Image: "batcher:latest"},
+		ModelPuller: v1beta1.ModelPullerConfig{Image: "puller:latest"},
+	}
+}
+
+func TestAddSidecarContainers_RoutesTrafficPortToLogger(t *testing.T) {
+	isvc := newTestPredictorIsvc()
+	addSidecarContainers(isvc, testSidecarConfig())
+
+	containers := isvc.Spec.Predictor.Custom.Spec.Containers
+	if len(containers) != 2 {
+		t.Fatalf("expected predictor + logger containers, got %d: %+v", len(containers), containers)
+	}
+	if containers[0].Ports != nil {
+		t.Errorf("expected predictor container to no longer carry the traffic port, got %+v", containers[0].Ports)
+	}
+	logger := containers[len(containers)-1]
+	if logger.Name != constants.LoggerContainerName {
+		t.Fatalf("expected logger container last, got %+v", logger)
+	}
+	if len(logger.Ports) != 1 || logger.Ports[0].ContainerPort != 8080 {
+		t.Errorf("expected traffic port routed to logger container, got %+v", logger.Ports)
+	}
+}
+
+func TestAddSidecarContainers_MMSAddsModelPullerAndVolume(t *testing.T) {
+	isvc := newTestPredictorIsvc()
+	isvc.Spec.Predictor.Logger = nil
+	isvc.Annotations = map[string]string{constants.MultiModelInternalAnnotationKey: "true"}
+
+	addSidecarContainers(isvc, testSidecarConfig())
+
+	spec := isvc.Spec.Predictor.Custom.Spec
+	if len(spec.Containers) != 2 {
+		t.Fatalf("expected predictor + model-puller containers, got %d: %+v", len(spec.Containers), spec.Containers)
+	}
+	puller := spec.Containers[len(spec.Containers)-1]
+	if puller.Name != constants.ModelPullerContainerName {
+		t.Fatalf("expected model-puller container, got %+v", puller)
+	}
+
+	foundVolume := false
+	for _, v := range spec.Volumes {
+		if v.Name == constants.ModelDirVolumeName {
+			foundVolume = true
+		}
+	}
+	if !foundVolume {
+		t.Errorf("expected %s volume on the pod spec, got %+v", constants.ModelDirVolumeName, spec.Volumes)
+	}
+
+	foundMount := false
+	for _, m := range spec.Containers[0].VolumeMounts {
+		if m.Name == constants.ModelDirVolumeName && m.MountPath == constants.ModelDirMountPath {
+			foundMount = true
+		}
+	}
+	if !foundMount {
+		t.Errorf("expected predictor container to mount %s, got %+v", constants.ModelDirVolumeName, spec.Containers[0].VolumeMounts)
+	}
+
+	// With no logger/batcher sidecar present, the predictor keeps its own traffic port.
+	if spec.Containers[0].Ports == nil {
+		t.Errorf("expected predictor to keep its traffic port when no logger/batcher sidecar is present")
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2020 kubeflow.org.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"github.com/go-logr/logr"
+	"github.com/kubeflow/kfserving/pkg/constants"
+	"github.com/kubeflow/kfserving/pkg/controller/v1beta1/inferenceservice/reconcilers/deployment"
+	"github.com/kubeflow/kfserving/pkg/controller/v1beta1/inferenceservice/reconcilers/knative"
+	"github.com/kubeflow/kfserving/pkg/credentials"
+	"github.com/kubeflow/kfserving/pkg/utils"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/kubeflow/kfserving/pkg/apis/serving/v1beta1"
+)
+
+var _ Component = &Transformer{}
+
+// Transformer reconciles resources for this component.
+type Transformer struct {
+	client                 client.Client
+	scheme                 *runtime.Scheme
+	inferenceServiceConfig *v1beta1.InferenceServicesConfig
+	credentialBuilder      *credentials.CredentialBuilder
+	Log                    logr.Logger
+}
+
+func NewTransformer(client client.Client, scheme *runtime.Scheme, inferenceServiceConfig *v1beta1.InferenceServicesConfig) Component {
+	return &Transformer{
+		client:                 client,
+		scheme:                 scheme,
+		inferenceServiceConfig: inferenceServiceConfig,
+		Log:                    ctrl.Log.WithName("TransformerReconciler"),
+	}
+}
+
+// Reconcile observes the world and attempts to drive the status towards the desired state.
+func (t *Transformer) Reconcile(isvc *v1beta1.InferenceService) error {
+	if isvc.Spec.Transformer == nil {
+		return nil
+	}
+	t.Log.Info("Reconciling Transformer", "TransformerSpec", isvc.Spec.Transformer)
+	transformer := isvc.Spec.Transformer.GetImplementation()
+	annotations := utils.Filter(isvc.Annotations, func(key string) bool {
+		return !utils.Includes(constants.ServiceAnnotationDisallowedList, key)
+	})
+
+	objectMeta := metav1.ObjectMeta{
+		Name:      isvc.Name + "-" + string(v1beta1.TransformerComponent),
+		Namespace: isvc.Namespace,
+		Labels: utils.Union(isvc.Labels, map[string]string{
+			constants.InferenceServicePodLabelKey: isvc.Name,
+			constants.KServiceComponentLabel:      string(v1beta1.TransformerComponent),
+		}),
+		Annotations: annotations,
+	}
+	if isvc.Spec.Transformer.Custom == nil {
+		container := transformer.GetContainer(isvc.ObjectMeta, isvc.Spec.Transformer.GetExtensions(), t.inferenceServiceConfig)
+		isvc.Spec.Transformer.Custom = &v1beta1.CustomTransformer{
+			PodTemplateSpec: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						*container,
+					},
+				},
+			},
+		}
+	} else {
+		container := transformer.GetContainer(isvc.ObjectMeta, isvc.Spec.Transformer.GetExtensions(), t.inferenceServiceConfig)
+		isvc.Spec.Transformer.Custom.Spec.Containers[0] = *container
+	}
+	// Keep the transformer's MODEL_NAME in lockstep with the predictor's, so the
+	// transformer's downstream URL and the predictor's registered model always agree.
+	setModelNameEnvVar(&isvc.Spec.Transformer.Custom.Spec.Containers[0], utils.GetModelName(isvc))
+
+	// Here we allow switch between knative and vanilla deployment
+	if getDeploymentMode(isvc.Annotations) == constants.RawDeployment {
+		r, err := deployment.NewDeploymentReconciler(t.client, t.scheme, objectMeta, &isvc.Spec.Transformer.ComponentExtensionSpec,
+			&isvc.Spec.Transformer.Custom.Spec, isvc.Status.Components[v1beta1.TransformerComponent], isvc)
+		if err != nil {
+			return err
+		}
+		status, err := r.Reconcile()
+		if err != nil {
+			return err
+		}
+		isvc.Status.PropagateStatus(v1beta1.TransformerComponent, status)
+		return nil
+	}
+
+	r := knative.NewKsvcReconciler(t.client, t.scheme, objectMeta, &isvc.Spec.Transformer.ComponentExtensionSpec,
+		&isvc.Spec.Transformer.Custom.Spec, isvc.Status.Components[v1beta1.TransformerComponent])
+
+	if err := controllerutil.SetControllerReference(isvc, r.Service, t.scheme); err != nil {
+		return err
+	}
+	if status, err := r.Reconcile(); err != nil {
+		return err
+	} else {
+		isvc.Status.PropagateStatus(v1beta1.TransformerComponent, status)
+		return nil
+	}
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2020 kubeflow.org.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package deployment reconciles a component (Predictor, Transformer or Explainer) as a
+// vanilla Kubernetes Deployment + Service + HorizontalPodAutoscaler, for clusters that do
+// not have Knative Serving installed. It is the RawDeployment counterpart of the
+// reconcilers/knative package.
+package deployment
+
+import (
+	"context"
+
+	"github.com/kubeflow/kfserving/pkg/apis/serving/v1beta1"
+	"github.com/kubeflow/kfserving/pkg/constants"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
+	v1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var log = ctrl.Log.WithName("DeploymentReconciler")
+
+// DeploymentReconciler reconciles a component as a Deployment, Service and HPA instead of
+// a Knative Service.
+type DeploymentReconciler struct {
+	client          client.Client
+	scheme          *runtime.Scheme
+	Deployment      *appsv1.Deployment
+	Service         *v1.Service
+	HPA             *autoscalingv2beta1.HorizontalPodAutoscaler
+	componentExt    *v1beta1.ComponentExtensionSpec
+	componentStatus v1beta1.ComponentStatusSpec
+}
+
+// NewDeploymentReconciler builds the Deployment, Service and HPA for componentMeta and sets
+// owner as their controller reference, so all three are garbage collected together when
+// owner is deleted.
+func NewDeploymentReconciler(client client.Client, scheme *runtime.Scheme, componentMeta metav1.ObjectMeta,
+	componentExt *v1beta1.ComponentExtensionSpec, podSpec *v1.PodSpec, componentStatus v1beta1.ComponentStatusSpec,
+	owner metav1.Object) (*DeploymentReconciler, error) {
+	r := &DeploymentReconciler{
+		client:          client,
+		scheme:          scheme,
+		Deployment:      createRawDeployment(componentMeta, componentExt, podSpec),
+		Service:         createRawService(componentMeta),
+		HPA:             createRawHPA(componentMeta, componentExt),
+		componentExt:    componentExt,
+		componentStatus: componentStatus,
+	}
+	for _, controlled := range []metav1.Object{r.Deployment, r.Service, r.HPA} {
+		if err := controllerutil.SetControllerReference(owner, controlled, scheme); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// selectorLabels returns the fixed subset of labels componentMeta always carries that is
+// safe to use as a Deployment/Service selector. Deployment.Spec.Selector is immutable once
+// created, so the selector must not include the InferenceService's own arbitrary, mutable
+// Labels (componentMeta.Labels is their union) or a later label edit would make the next
+// reconcile try to change the selector and get rejected by the API server.
+func selectorLabels(labels map[string]string) map[string]string {
+	selector := make(map[string]string, 2)
+	for _, key := range []string{constants.InferenceServicePodLabelKey, constants.KServiceComponentLabel} {
+		if value, ok := labels[key]; ok {
+			selector[key] = value
+		}
+	}
+	return selector
+}
+
+func createRawDeployment(componentMeta metav1.ObjectMeta, componentExt *v1beta1.ComponentExtensionSpec, podSpec *v1.PodSpec) *appsv1.Deployment {
+	var replicas int32 = 1
+	if componentExt.MinReplicas != nil {
+		replicas = int32(*componentExt.MinReplicas)
+	}
+	return &appsv1.Deployment{
+		ObjectMeta: componentMeta,
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: selectorLabels(componentMeta.Labels),
+			},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: componentMeta,
+				Spec:       *podSpec,
+			},
+		},
+	}
+}
+
+func createRawService(componentMeta metav1.ObjectMeta) *v1.Service {
+	return &v1.Service{
+		ObjectMeta: componentMeta,
+		Spec: v1.ServiceSpec{
+			Selector: selectorLabels(componentMeta.Labels),
+			Ports: []v1.ServicePort{
+				{
+					Name:       "http",
+					Port:       80,
+					TargetPort: intstr.FromInt(constants.InferenceServiceDefaultHttpPort),
+				},
+			},
+		},
+	}
+}
+
+func createRawHPA(componentMeta metav1.ObjectMeta, componentExt *v1beta1.ComponentExtensionSpec) *autoscalingv2beta1.HorizontalPodAutoscaler {
+	minReplicas := int32(1)
+	if componentExt.MinReplicas != nil {
+		minReplicas = int32(*componentExt.MinReplicas)
+	}
+	maxReplicas := minReplicas
+	if int32(componentExt.MaxReplicas) > minReplicas {
+		maxReplicas = int32(componentExt.MaxReplicas)
+	}
+	// A vanilla HorizontalPodAutoscaler has no way to scale on concurrent requests the
+	// way Knative's KPA does, so ContainerConcurrency isn't applicable here: scale on CPU
+	// utilization instead, always targeting constants.DefaultCPUUtilization.
+	targetUtilization := constants.DefaultCPUUtilization
+	return &autoscalingv2beta1.HorizontalPodAutoscaler{
+		ObjectMeta: componentMeta,
+		Spec: autoscalingv2beta1.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2beta1.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       componentMeta.Name,
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: maxReplicas,
+			Metrics: []autoscalingv2beta1.MetricSpec{
+				{
+					Type: autoscalingv2beta1.ResourceMetricSourceType,
+					Resource: &autoscalingv2beta1.ResourceMetricSource{
+						Name:                     v1.ResourceCPU,
+						TargetAverageUtilization: &targetUtilization,
+					},
+				},
+			},
+		},
+	}
+}
+
+// Reconcile creates or updates the Deployment, Service and HPA and returns the component's
+// status so callers can propagate it the same way they do for a Knative reconcile. It only
+// reports the component as ready once the Deployment's rollout has actually converged;
+// until then it returns a nil status so PropagateStatus keeps the component as not-ready.
+func (r *DeploymentReconciler) Reconcile() (*v1beta1.StatusConfigurationSpec, error) {
+	if err := r.reconcileDeployment(); err != nil {
+		return nil, err
+	}
+	if err := r.reconcileService(); err != nil {
+		return nil, err
+	}
+	if err := r.reconcileHPA(); err != nil {
+		return nil, err
+	}
+
+	existing := &appsv1.Deployment{}
+	if err := r.client.Get(context.TODO(), client.ObjectKey{Name: r.Deployment.Name, Namespace: r.Deployment.Namespace}, existing); err != nil {
+		return nil, err
+	}
+	if !deploymentIsReady(existing) {
+		log.Info("Deployment rollout not yet ready", "name", r.Deployment.Name,
+			"readyReplicas", existing.Status.ReadyReplicas, "previousStatus", r.componentStatus)
+		return nil, nil
+	}
+	return &v1beta1.StatusConfigurationSpec{Name: r.Deployment.Name}, nil
+}
+
+// deploymentIsReady reports whether deployment has rolled out: at least as many ready
+// replicas as desired, and an Available condition of True.
+func deploymentIsReady(deployment *appsv1.Deployment) bool {
+	desiredReplicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desiredReplicas = *deployment.Spec.Replicas
+	}
+	if deployment.Status.ReadyReplicas < desiredReplicas {
+		return false
+	}
+	for _, cond := range deployment.Status.Conditions {
+		if cond.Type == appsv1.DeploymentAvailable {
+			return cond.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func (r *DeploymentReconciler) reconcileDeployment() error {
+	existing := &appsv1.Deployment{}
+	err := r.client.Get(context.TODO(), client.ObjectKey{Name: r.Deployment.Name, Namespace: r.Deployment.Namespace}, existing)
+	if err != nil {
+		if apierr.IsNotFound(err) {
+			log.Info("Creating Deployment", "name", r.Deployment.Name)
+			return r.client.Create(context.TODO(), r.Deployment)
+		}
+		return err
+	}
+	existing.Spec = r.Deployment.Spec
+	log.Info("Updating Deployment", "name", r.Deployment.Name)
+	return r.client.Update(context.TODO(), existing)
+}
+
+func (r *DeploymentReconciler) reconcileService() error {
+	existing := &v1.Service{}
+	err := r.client.Get(context.TODO(), client.ObjectKey{Name: r.Service.Name, Namespace: r.Service.Namespace}, existing)
+	if err != nil {
+		if apierr.IsNotFound(err) {
+			log.Info("Creating Service", "name", r.Service.Name)
+			return r.client.Create(context.TODO(), r.Service)
+		}
+		return err
+	}
+	r.Service.Spec.ClusterIP = existing.Spec.ClusterIP
+	existing.Spec = r.Service.Spec
+	log.Info("Updating Service", "name", r.Service.Name)
+	return r.client.Update(context.TODO(), existing)
+}
+
+func (r *DeploymentReconciler) reconcileHPA() error {
+	existing := &autoscalingv2beta1.HorizontalPodAutoscaler{}
+	err := r.client.Get(context.TODO(), client.ObjectKey{Name: r.HPA.Name, Namespace: r.HPA.Namespace}, existing)
+	if err != nil {
+		if apierr.IsNotFound(err) {
+			log.Info("Creating HorizontalPodAutoscaler", "name", r.HPA.Name)
+			return r.client.Create(context.TODO(), r.HPA)
+		}
+		return err
+	}
+	existing.Spec = r.HPA.Spec
+	log.Info("Updating HorizontalPodAutoscaler", "name", r.HPA.Name)
+	return r.client.Update(context.TODO(), existing)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2020 kubeflow.org.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubeflow/kfserving/pkg/apis/serving/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestComponentMeta() metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      "my-isvc-predictor",
+		Namespace: "default",
+		Labels:    map[string]string{"serving.kubeflow.org/inferenceservice": "my-isvc"},
+	}
+}
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add appsv1 to scheme: %v", err)
+	}
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add corev1 to scheme: %v", err)
+	}
+	if err := autoscalingv2beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add autoscalingv2beta1 to scheme: %v", err)
+	}
+	if err := v1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add v1beta1 to scheme: %v", err)
+	}
+	return scheme
+}
+
+func newTestOwner() *v1beta1.InferenceService {
+	return &v1beta1.InferenceService{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-isvc", Namespace: "default"},
+	}
+}
+
+func TestNewDeploymentReconciler_UsesMinMaxReplicasForHPA(t *testing.T) {
+	minReplicas := 2
+	maxReplicas := 5
+	componentExt := &v1beta1.ComponentExtensionSpec{MinReplicas: &minReplicas, MaxReplicas: maxReplicas}
+
+	scheme := newTestScheme(t)
+	r, err := NewDeploymentReconciler(fake.NewClientBuilder().WithScheme(scheme).Build(), scheme,
+		newTestComponentMeta(), componentExt, &v1.PodSpec{Containers: []v1.Container{{Name: "predictor"}}},
+		v1beta1.ComponentStatusSpec{}, newTestOwner())
+	if err != nil {
+		t.Fatalf("NewDeploymentReconciler returned error: %v", err)
+	}
+
+	if *r.Deployment.Spec.Replicas != int32(minReplicas) {
+		t.Errorf("expected deployment replicas %d, got %d", minReplicas, *r.Deployment.Spec.Replicas)
+	}
+	if *r.HPA.Spec.MinReplicas != int32(minReplicas) {
+		t.Errorf("expected HPA min replicas %d, got %d", minReplicas, *r.HPA.Spec.MinReplicas)
+	}
+	if r.HPA.Spec.MaxReplicas != int32(maxReplicas) {
+		t.Errorf("expected HPA max replicas %d, got %d", maxReplicas, r.HPA.Spec.MaxReplicas)
+	}
+	if len(r.HPA.Spec.Metrics) != 1 || r.HPA.Spec.Metrics[0].Resource.Name != v1.ResourceCPU {
+		t.Fatalf("expected a single CPU resource metric, got %+v", r.HPA.Spec.Metrics)
+	}
+}
+
+func TestReconcile_NotReadyUntilDeploymentRolloutConverges(t *testing.T) {
+	scheme := newTestScheme(t)
+	minReplicas := 1
+	componentExt := &v1beta1.ComponentExtensionSpec{MinReplicas: &minReplicas, MaxReplicas: 1}
+	meta := newTestComponentMeta()
+	podSpec := &v1.PodSpec{Containers: []v1.Container{{Name: "predictor"}}}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	r, err := NewDeploymentReconciler(c, scheme, meta, componentExt, podSpec, v1beta1.ComponentStatusSpec{}, newTestOwner())
+	if err != nil {
+		t.Fatalf("NewDeploymentReconciler returned error: %v", err)
+	}
+
+	status, err := r.Reconcile()
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if status != nil {
+		t.Fatalf("expected nil status before the Deployment rollout converges, got %+v", status)
+	}
+
+	existing := &appsv1.Deployment{}
+	if err := c.Get(context.TODO(), client.ObjectKey{Name: meta.Name, Namespace: meta.Namespace}, existing); err != nil {
+		t.Fatalf("expected deployment to be created: %v", err)
+	}
+	existing.Status.ReadyReplicas = 1
+	existing.Status.Conditions = []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: v1.ConditionTrue}}
+	if err := c.Update(context.TODO(), existing); err != nil {
+		t.Fatalf("failed to update deployment status: %v", err)
+	}
+
+	status, err = r.Reconcile()
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if status == nil || status.Name != meta.Name {
+		t.Fatalf("expected ready status with name %s, got %+v", meta.Name, status)
+	}
+}
+
+func TestNewDeploymentReconciler_SetsOwnerReferenceOnDeploymentServiceAndHPA(t *testing.T) {
+	scheme := newTestScheme(t)
+	minReplicas := 1
+	componentExt := &v1beta1.ComponentExtensionSpec{MinReplicas: &minReplicas, MaxReplicas: 1}
+	owner := newTestOwner()
+
+	r, err := NewDeploymentReconciler(fake.NewClientBuilder().WithScheme(scheme).Build(), scheme,
+		newTestComponentMeta(), componentExt, &v1.PodSpec{Containers: []v1.Container{{Name: "predictor"}}},
+		v1beta1.ComponentStatusSpec{}, owner)
+	if err != nil {
+		t.Fatalf("NewDeploymentReconciler returned error: %v", err)
+	}
+
+	for name, refs := range map[string][]metav1.OwnerReference{
+		"Deployment": r.Deployment.OwnerReferences,
+		"Service":    r.Service.OwnerReferences,
+		"HPA":        r.HPA.OwnerReferences,
+	} {
+		if len(refs) != 1 || refs[0].Name != owner.Name || !*refs[0].Controller {
+			t.Errorf("expected %s to have owner %s as its controller, got %+v", name, owner.Name, refs)
+		}
+	}
+}
+
+func TestSelectorLabels_ExcludesArbitraryInferenceServiceLabels(t *testing.T) {
+	meta := newTestComponentMeta()
+	meta.Labels["user-supplied-label"] = "whatever the user wants"
+
+	selector := selectorLabels(meta.Labels)
+
+	if _, ok := selector["user-supplied-label"]; ok {
+		t.Errorf("expected arbitrary InferenceService labels to be excluded from the selector, got %+v", selector)
+	}
+	if len(selector) != 1 || selector["serving.kubeflow.org/inferenceservice"] != "my-isvc" {
+		t.Errorf("expected selector to only contain the fixed label set, got %+v", selector)
+	}
+}
@@ -18,38 +18,186 @@ package multimodelconfig
 
 import (
 	"context"
+	"fmt"
+
 	v1beta1api "github.com/kubeflow/kfserving/pkg/apis/serving/v1beta1"
+	"github.com/kubeflow/kfserving/pkg/constants"
+	"github.com/kubeflow/kfserving/pkg/modelconfig"
 	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
 
 var log = logf.Log.WithName("Reconciler")
 
 type ConfigMapReconciler struct {
-	client client.Client
-	scheme *runtime.Scheme
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
 }
 
-func NewConfigMapReconciler(client client.Client, scheme *runtime.Scheme) *ConfigMapReconciler {
+func NewConfigMapReconciler(client client.Client, scheme *runtime.Scheme, recorder record.EventRecorder) *ConfigMapReconciler {
 	return &ConfigMapReconciler{
-		client: client,
-		scheme: scheme,
+		client:   client,
+		scheme:   scheme,
+		recorder: recorder,
 	}
 }
 
+// Reconcile adds, updates or removes trainedModel's entry in the multi-model ConfigMap
+// identified by desired.ObjectMeta, creating the ConfigMap when it does not yet exist and
+// deleting it once the last model entry is removed.
 func (c *ConfigMapReconciler) Reconcile(desired *corev1.ConfigMap, trainedModel *v1beta1api.TrainedModel) error {
 	if trainedModel.DeletionTimestamp != nil {
-		//A Trainedmodel is being deleted, remove the model from multi-model configmap
-		//TODO call multimodelconfig handler once https://github.com/kubeflow/kfserving/pull/992 is merged
-	} else {
-		//A Trainedmodel is created or updated, add or update the model from multi-model configmap
-		//TODO call multimodelconfig handler once https://github.com/kubeflow/kfserving/pull/992 is merged
+		return c.deleteModelFromConfigMap(desired, trainedModel)
 	}
-	err := c.client.Create(context.TODO(), desired)
+	return c.addOrUpdateModelInConfigMap(desired, trainedModel)
+}
+
+func (c *ConfigMapReconciler) addOrUpdateModelInConfigMap(desired *corev1.ConfigMap, trainedModel *v1beta1api.TrainedModel) error {
+	modelConfig := modelconfig.ModelConfig{
+		ModelName: trainedModel.Name,
+		Spec:      trainedModel.Spec.Model,
+	}
+	delta := modelconfig.NewConfigsDelta(modelconfig.ModelConfigs{modelConfig}, nil)
+
+	created := false
+	changed := false
+	// A concurrent reconcile of another model in the same shard may create the ConfigMap
+	// first, so a Create that fails with AlreadyExists is retried like an Update conflict.
+	err := retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return apierr.IsConflict(err) || apierr.IsAlreadyExists(err)
+	}, func() error {
+		created, changed = false, false
+		configMap, isNew, err := c.getOrInitConfigMap(desired, trainedModel)
+		if err != nil {
+			return err
+		}
+		created = isNew
+		_, configChanged, err := delta.Process(configMap)
+		if err != nil {
+			return err
+		}
+		if !configChanged {
+			// The model is already registered with this exact spec, e.g. a duplicate or
+			// late reconcile. Leave the ConfigMap untouched.
+			return nil
+		}
+		changed = true
+		return c.save(configMap, isNew)
+	})
 	if err != nil {
+		c.recorder.Eventf(trainedModel, corev1.EventTypeWarning, "ModelConfigFailed",
+			"Failed to add model %s to configmap %s: %v", trainedModel.Name, desired.Name, err)
 		return err
 	}
+	if !changed {
+		return nil
+	}
+
+	reason := "ModelUpdated"
+	if created {
+		reason = "ModelAdded"
+	}
+	c.recorder.Eventf(trainedModel, corev1.EventTypeNormal, reason,
+		"Model %s is added/updated in configmap %s", trainedModel.Name, desired.Name)
+	return nil
+}
+
+func (c *ConfigMapReconciler) deleteModelFromConfigMap(desired *corev1.ConfigMap, trainedModel *v1beta1api.TrainedModel) error {
+	delta := modelconfig.NewConfigsDelta(nil, []string{trainedModel.Name})
+
+	removed := false
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		removed = false
+		configMap := &corev1.ConfigMap{}
+		if err := c.client.Get(context.TODO(), types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, configMap); err != nil {
+			if apierr.IsNotFound(err) {
+				// Nothing to remove, the configmap or model entry is already gone.
+				return nil
+			}
+			return err
+		}
+
+		remaining, changed, err := delta.Process(configMap)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			// The model was already absent from this shard, e.g. a duplicate or late
+			// delete reconcile. Leave the ConfigMap untouched.
+			return nil
+		}
+		removed = true
+		if len(remaining) == 0 {
+			if err := c.client.Delete(context.TODO(), configMap); err != nil && !apierr.IsNotFound(err) {
+				return err
+			}
+			return nil
+		}
+		return c.client.Update(context.TODO(), configMap)
+	})
+	if err != nil {
+		c.recorder.Eventf(trainedModel, corev1.EventTypeWarning, "ModelConfigFailed",
+			"Failed to remove model %s from configmap %s: %v", trainedModel.Name, desired.Name, err)
+		return err
+	}
+
+	if removed {
+		c.recorder.Eventf(trainedModel, corev1.EventTypeNormal, "ModelRemoved",
+			"Model %s is removed from configmap %s", trainedModel.Name, desired.Name)
+	}
 	return nil
 }
+
+// getOrInitConfigMap fetches the current state of the shard's ConfigMap, or returns a fresh
+// copy of desired (with the owning InferenceService set as its controller) when it does not
+// yet exist. The returned bool reports whether the ConfigMap still needs to be created.
+func (c *ConfigMapReconciler) getOrInitConfigMap(desired *corev1.ConfigMap, trainedModel *v1beta1api.TrainedModel) (*corev1.ConfigMap, bool, error) {
+	configMap := &corev1.ConfigMap{}
+	err := c.client.Get(context.TODO(), types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, configMap)
+	if err == nil {
+		return configMap, false, nil
+	}
+	if !apierr.IsNotFound(err) {
+		return nil, false, err
+	}
+
+	configMap = desired.DeepCopy()
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	if err := c.setOwnerReference(configMap, trainedModel); err != nil {
+		return nil, false, err
+	}
+	return configMap, true, nil
+}
+
+func (c *ConfigMapReconciler) save(configMap *corev1.ConfigMap, isNew bool) error {
+	if isNew {
+		return c.client.Create(context.TODO(), configMap)
+	}
+	return c.client.Update(context.TODO(), configMap)
+}
+
+// setOwnerReference sets the parent InferenceService named by trainedModel's
+// ParentInferenceServiceLabel as the ConfigMap's owner, so the shard is garbage collected
+// when the InferenceService is deleted.
+func (c *ConfigMapReconciler) setOwnerReference(configMap *corev1.ConfigMap, trainedModel *v1beta1api.TrainedModel) error {
+	isvcName, ok := trainedModel.Labels[constants.ParentInferenceServiceLabel]
+	if !ok {
+		return fmt.Errorf("trainedmodel %s is missing the %s label", trainedModel.Name, constants.ParentInferenceServiceLabel)
+	}
+
+	isvc := &v1beta1api.InferenceService{}
+	if err := c.client.Get(context.TODO(), types.NamespacedName{Name: isvcName, Namespace: trainedModel.Namespace}, isvc); err != nil {
+		return err
+	}
+	return controllerutil.SetControllerReference(isvc, configMap, c.scheme)
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2020 kubeflow.org.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multimodelconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	v1beta1api "github.com/kubeflow/kfserving/pkg/apis/serving/v1beta1"
+	"github.com/kubeflow/kfserving/pkg/constants"
+	"github.com/kubeflow/kfserving/pkg/modelconfig"
+	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNamespace = "default"
+	testIsvcName  = "my-model"
+	testConfigMap = "modelconfig-my-model"
+)
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add corev1 to scheme: %v", err)
+	}
+	if err := v1beta1api.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add v1beta1 to scheme: %v", err)
+	}
+	return scheme
+}
+
+func newDesiredConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testConfigMap,
+			Namespace: testNamespace,
+		},
+	}
+}
+
+func newTrainedModel(name string) *v1beta1api.TrainedModel {
+	return &v1beta1api.TrainedModel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels: map[string]string{
+				constants.ParentInferenceServiceLabel: testIsvcName,
+			},
+		},
+		Spec: v1beta1api.TrainedModelSpec{
+			Model: v1beta1api.ModelSpec{
+				StorageURI: "s3://bucket/" + name,
+				Framework:  "sklearn",
+			},
+		},
+	}
+}
+
+func TestConfigMapReconciler_AddOrUpdate_CreatesConfigMapWithOwnerReference(t *testing.T) {
+	scheme := newTestScheme(t)
+	isvc := &v1beta1api.InferenceService{ObjectMeta: metav1.ObjectMeta{Name: testIsvcName, Namespace: testNamespace}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(isvc).Build()
+	reconciler := NewConfigMapReconciler(c, scheme, record.NewFakeRecorder(10))
+
+	tm := newTrainedModel("model-a")
+	if err := reconciler.Reconcile(newDesiredConfigMap(), tm); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: testConfigMap, Namespace: testNamespace}, configMap); err != nil {
+		t.Fatalf("expected configmap to be created: %v", err)
+	}
+	if len(configMap.OwnerReferences) != 1 || configMap.OwnerReferences[0].Name != testIsvcName {
+		t.Fatalf("expected configmap to be owned by %s, got %+v", testIsvcName, configMap.OwnerReferences)
+	}
+	if configMap.Data[modelconfig.ModelConfigFileName] == "" {
+		t.Fatalf("expected %s to be populated", modelconfig.ModelConfigFileName)
+	}
+}
+
+func TestConfigMapReconciler_AddOrUpdate_NoOpWhenModelAlreadyRegistered(t *testing.T) {
+	scheme := newTestScheme(t)
+	isvc := &v1beta1api.InferenceService{ObjectMeta: metav1.ObjectMeta{Name: testIsvcName, Namespace: testNamespace}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(isvc).Build()
+	recorder := record.NewFakeRecorder(10)
+	reconciler := NewConfigMapReconciler(c, scheme, recorder)
+
+	tm := newTrainedModel("model-a")
+	if err := reconciler.Reconcile(newDesiredConfigMap(), tm); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	// Drain the event the first reconcile legitimately fired.
+	<-recorder.Events
+
+	if err := reconciler.Reconcile(newDesiredConfigMap(), tm); err != nil {
+		t.Fatalf("repeat Reconcile returned error: %v", err)
+	}
+	select {
+	case event := <-recorder.Events:
+		t.Fatalf("expected no event when the model was already registered with the same spec, got %q", event)
+	default:
+	}
+}
+
+func TestConfigMapReconciler_Delete_RemovesConfigMapWhenEmpty(t *testing.T) {
+	scheme := newTestScheme(t)
+	isvc := &v1beta1api.InferenceService{ObjectMeta: metav1.ObjectMeta{Name: testIsvcName, Namespace: testNamespace}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(isvc).Build()
+	reconciler := NewConfigMapReconciler(c, scheme, record.NewFakeRecorder(10))
+
+	tm := newTrainedModel("model-a")
+	if err := reconciler.Reconcile(newDesiredConfigMap(), tm); err != nil {
+		t.Fatalf("add Reconcile returned error: %v", err)
+	}
+
+	now := metav1.Now()
+	tm.DeletionTimestamp = &now
+	if err := reconciler.Reconcile(newDesiredConfigMap(), tm); err != nil {
+		t.Fatalf("delete Reconcile returned error: %v", err)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: testConfigMap, Namespace: testNamespace}, configMap)
+	if !apierr.IsNotFound(err) {
+		t.Fatalf("expected configmap to be deleted once empty, got err=%v", err)
+	}
+}
+
+// TestConfigMapReconciler_ConcurrentUpdatesToSameShard reconciles several TrainedModels
+// that share the same ConfigMap shard in parallel, and asserts the optimistic-concurrency
+// retry loop means every model ends up recorded even though they race on the same object.
+func TestConfigMapReconciler_ConcurrentUpdatesToSameShard(t *testing.T) {
+	scheme := newTestScheme(t)
+	isvc := &v1beta1api.InferenceService{ObjectMeta: metav1.ObjectMeta{Name: testIsvcName, Namespace: testNamespace}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(isvc).Build()
+	reconciler := NewConfigMapReconciler(c, scheme, record.NewFakeRecorder(50))
+
+	const modelCount = 10
+	var wg sync.WaitGroup
+	for i := 0; i < modelCount; i++ {
+		tm := newTrainedModel(fmt.Sprintf("model-%d", i))
+		wg.Add(1)
+		go func(tm *v1beta1api.TrainedModel) {
+			defer wg.Done()
+			if err := reconciler.Reconcile(newDesiredConfigMap(), tm); err != nil {
+				t.Errorf("concurrent Reconcile returned error: %v", err)
+			}
+		}(tm)
+	}
+	wg.Wait()
+
+	configMap := &corev1.ConfigMap{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: testConfigMap, Namespace: testNamespace}, configMap); err != nil {
+		t.Fatalf("expected configmap to exist: %v", err)
+	}
+	var models modelconfig.ModelConfigs
+	raw := configMap.Data[modelconfig.ModelConfigFileName]
+	if err := json.Unmarshal([]byte(raw), &models); err != nil {
+		t.Fatalf("failed to unmarshal models: %v", err)
+	}
+	if len(models) != modelCount {
+		t.Fatalf("expected %d models in the shard, got %d: %v", modelCount, len(models), models)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2020 kubeflow.org.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package modelconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/kubeflow/kfserving/pkg/apis/serving/v1beta1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// ModelConfigFileName is the key in a multi-model ConfigMap's Data map under which the
+// JSON-encoded list of models assigned to that shard is stored.
+const ModelConfigFileName = "models.json"
+
+// ModelConfig holds everything a multi-model server sidecar needs to load a single model.
+type ModelConfig struct {
+	ModelName string            `json:"modelName"`
+	Spec      v1beta1.ModelSpec `json:"modelSpec"`
+}
+
+// ModelConfigs is the JSON-serializable contents of a shard's ModelConfigFileName entry.
+type ModelConfigs []ModelConfig
+
+// ConfigsDelta captures the models that should be added/updated and the model names that
+// should be removed the next time a multi-model ConfigMap shard is reconciled.
+type ConfigsDelta struct {
+	updated ModelConfigs
+	deleted []string
+}
+
+// NewConfigsDelta creates a ConfigsDelta from the models to upsert and the model names to remove.
+func NewConfigsDelta(updated ModelConfigs, deleted []string) *ConfigsDelta {
+	return &ConfigsDelta{updated: updated, deleted: deleted}
+}
+
+// Process applies the delta to configMap's ModelConfigFileName entry and returns the
+// resulting list of models and whether applying the delta actually changed anything, so
+// the caller can tell whether the shard is now empty and whether a write is needed at all.
+func (c *ConfigsDelta) Process(configMap *v1.ConfigMap) (ModelConfigs, bool, error) {
+	current, err := parseModelConfigs(configMap)
+	if err != nil {
+		return nil, false, err
+	}
+
+	changed := false
+	index := make(map[string]int, len(current))
+	for i, m := range current {
+		index[m.ModelName] = i
+	}
+	for _, m := range c.updated {
+		if i, ok := index[m.ModelName]; ok {
+			if !reflect.DeepEqual(current[i], m) {
+				current[i] = m
+				changed = true
+			}
+		} else {
+			index[m.ModelName] = len(current)
+			current = append(current, m)
+			changed = true
+		}
+	}
+
+	if len(c.deleted) > 0 {
+		toDelete := make(map[string]bool, len(c.deleted))
+		for _, name := range c.deleted {
+			toDelete[name] = true
+		}
+		filtered := current[:0]
+		for _, m := range current {
+			if toDelete[m.ModelName] {
+				changed = true
+				continue
+			}
+			filtered = append(filtered, m)
+		}
+		current = filtered
+	}
+
+	if !changed {
+		return current, false, nil
+	}
+
+	encoded, err := json.Marshal(current)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal model configs: %w", err)
+	}
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[ModelConfigFileName] = string(encoded)
+	return current, true, nil
+}
+
+func parseModelConfigs(configMap *v1.ConfigMap) (ModelConfigs, error) {
+	raw, ok := configMap.Data[ModelConfigFileName]
+	if !ok || raw == "" {
+		return ModelConfigs{}, nil
+	}
+	configs := ModelConfigs{}
+	if err := json.Unmarshal([]byte(raw), &configs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal model configs: %w", err)
+	}
+	return configs, nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 kubeflow.org.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"github.com/kubeflow/kfserving/pkg/apis/serving/v1beta1"
+	"github.com/kubeflow/kfserving/pkg/constants"
+	v1 "k8s.io/api/core/v1"
+)
+
+// GetModelName returns the served model name for isvc: the MODEL_NAME env var or
+// --model_name arg a user set on the predictor or transformer container, if any, otherwise
+// isvc.Name. Predictor, Transformer and Explainer reconcilers should all call this so the
+// transformer's downstream URL and the predictor's registered model name always agree.
+func GetModelName(isvc *v1beta1.InferenceService) string {
+	if isvc.Spec.Predictor.Custom != nil {
+		if name, ok := modelNameFromContainers(isvc.Spec.Predictor.Custom.Spec.Containers); ok {
+			return name
+		}
+	}
+	if isvc.Spec.Transformer != nil && isvc.Spec.Transformer.Custom != nil {
+		if name, ok := modelNameFromContainers(isvc.Spec.Transformer.Custom.Spec.Containers); ok {
+			return name
+		}
+	}
+	return isvc.Name
+}
+
+func modelNameFromContainers(containers []v1.Container) (string, bool) {
+	for _, container := range containers {
+		for _, env := range container.Env {
+			if env.Name == constants.ModelNameEnvVarKey {
+				return env.Value, true
+			}
+		}
+		for i, arg := range container.Args {
+			if arg == "--model_name" && i+1 < len(container.Args) {
+				return container.Args[i+1], true
+			}
+		}
+	}
+	return "", false
+}
+
+// IsMMSPredictor reports whether isvc's predictor is running in multi-model server mode,
+// in which case its models are synced by the model-puller sidecar from the multi-model
+// ConfigMap instead of a single STORAGE_URI/MODEL_NAME pair.
+func IsMMSPredictor(isvc *v1beta1.InferenceService) bool {
+	return isvc.Annotations[constants.MultiModelInternalAnnotationKey] == "true"
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2020 kubeflow.org.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/kubeflow/kfserving/pkg/apis/serving/v1beta1"
+	"github.com/kubeflow/kfserving/pkg/constants"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestIsvc(name string) *v1beta1.InferenceService {
+	return &v1beta1.InferenceService{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+	}
+}
+
+func TestGetModelName_FallsBackToIsvcName(t *testing.T) {
+	isvc := newTestIsvc("my-model")
+	if name := GetModelName(isvc); name != "my-model" {
+		t.Errorf("expected fallback to isvc name, got %q", name)
+	}
+}
+
+func TestGetModelName_UsesPredictorEnvVar(t *testing.T) {
+	isvc := newTestIsvc("my-model")
+	isvc.Spec.Predictor.Custom = &v1beta1.CustomPredictor{
+		PodTemplateSpec: v1.PodTemplateSpec{
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{
+						Name: "kfserving-container",
+						Env:  []v1.EnvVar{{Name: "MODEL_NAME", Value: "custom-name"}},
+					},
+				},
+			},
+		},
+	}
+	if name := GetModelName(isvc); name != "custom-name" {
+		t.Errorf("expected custom MODEL_NAME env var to win, got %q", name)
+	}
+}
+
+func TestGetModelName_UsesPredictorModelNameArg(t *testing.T) {
+	isvc := newTestIsvc("my-model")
+	isvc.Spec.Predictor.Custom = &v1beta1.CustomPredictor{
+		PodTemplateSpec: v1.PodTemplateSpec{
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{
+						Name: "kfserving-container",
+						Args: []string{"--model_name", "arg-name"},
+					},
+				},
+			},
+		},
+	}
+	if name := GetModelName(isvc); name != "arg-name" {
+		t.Errorf("expected --model_name arg to win, got %q", name)
+	}
+}
+
+func TestGetModelName_FallsBackToTransformerWhenPredictorUnset(t *testing.T) {
+	isvc := newTestIsvc("my-model")
+	isvc.Spec.Transformer = &v1beta1.TransformerSpec{
+		Custom: &v1beta1.CustomTransformer{
+			PodTemplateSpec: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{
+							Name: "transformer-container",
+							Env:  []v1.EnvVar{{Name: "MODEL_NAME", Value: "transformer-name"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	if name := GetModelName(isvc); name != "transformer-name" {
+		t.Errorf("expected transformer MODEL_NAME env var, got %q", name)
+	}
+}
+
+func TestIsMMSPredictor(t *testing.T) {
+	isvc := newTestIsvc("my-model")
+	if IsMMSPredictor(isvc) {
+		t.Errorf("expected non-MMS predictor by default")
+	}
+	isvc.Annotations = map[string]string{constants.MultiModelInternalAnnotationKey: "true"}
+	if !IsMMSPredictor(isvc) {
+		t.Errorf("expected MMS predictor once internal annotation is set")
+	}
+}